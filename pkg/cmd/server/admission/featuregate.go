@@ -0,0 +1,50 @@
+package admission
+
+import (
+	"fmt"
+	"time"
+)
+
+// FeatureGates is a minimal, read-only view of which named feature gates are currently enabled.  It
+// exists so admission plugins don't need to depend on whatever flag-parsing package happens to own the
+// authoritative set.
+type FeatureGates interface {
+	Enabled(feature string) bool
+}
+
+// FeatureGateAccessor gives admission plugins a live view of the enabled feature gates, along with a
+// way to know once that view reflects a real observation rather than just its zero value.  This allows
+// gates to be sourced from something that isn't available at flag-parsing time (e.g. a ConfigMap),
+// while still letting plugins block admission until they have seen a real value at least once.
+type FeatureGateAccessor interface {
+	// InitialFeatureGatesObserved returns a channel that is closed once CurrentFeatureGates reflects a
+	// real observation.
+	InitialFeatureGatesObserved() <-chan struct{}
+
+	// CurrentFeatureGates returns the most recently observed set of feature gates.
+	CurrentFeatureGates() FeatureGates
+}
+
+// WantsFeatureGates should be implemented by admission plugins that need to gate behavior behind
+// feature gates instead of hardcoding it on or off at build time.  PluginInitializer.Initialize wires
+// the accessor in; a plugin that also implements Validator is expected to call
+// WaitForInitialFeatureGates from its own Validate() before trusting CurrentFeatureGates, so it never
+// silently admits everything (or nothing) while still waiting on its first observation.
+type WantsFeatureGates interface {
+	SetFeatureGateAccessor(FeatureGateAccessor)
+}
+
+// WaitForInitialFeatureGates blocks until accessor has observed its initial set of feature gates, or
+// returns an error if timeout elapses first.  Plugins that implement WantsFeatureGates should call this
+// from their Validate() before relying on CurrentFeatureGates.
+func WaitForInitialFeatureGates(accessor FeatureGateAccessor, timeout time.Duration) error {
+	if accessor == nil {
+		return fmt.Errorf("no FeatureGateAccessor was configured")
+	}
+	select {
+	case <-accessor.InitialFeatureGatesObserved():
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("timed out after %s waiting for initial feature gates to be observed", timeout)
+	}
+}
@@ -0,0 +1,43 @@
+package admission
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeFeatureGateAccessor is a FeatureGateAccessor whose observed channel the test controls directly,
+// so it can exercise both the "observed in time" and "never observed" paths deterministically.
+type fakeFeatureGateAccessor struct {
+	observed chan struct{}
+	gates    FeatureGates
+}
+
+func (f *fakeFeatureGateAccessor) InitialFeatureGatesObserved() <-chan struct{} { return f.observed }
+func (f *fakeFeatureGateAccessor) CurrentFeatureGates() FeatureGates            { return f.gates }
+
+func TestWaitForInitialFeatureGates(t *testing.T) {
+	t.Run("returns nil once the accessor observes gates before the timeout", func(t *testing.T) {
+		accessor := &fakeFeatureGateAccessor{observed: make(chan struct{})}
+		close(accessor.observed)
+
+		if err := WaitForInitialFeatureGates(accessor, time.Second); err != nil {
+			t.Fatalf("expected no error once gates were observed, got: %v", err)
+		}
+	})
+
+	t.Run("times out with an error if the accessor never observes gates", func(t *testing.T) {
+		accessor := &fakeFeatureGateAccessor{observed: make(chan struct{})}
+
+		err := WaitForInitialFeatureGates(accessor, 10*time.Millisecond)
+		if err == nil {
+			t.Fatal("expected a timeout error, got none")
+		}
+	})
+
+	t.Run("returns an immediate error for a nil accessor", func(t *testing.T) {
+		err := WaitForInitialFeatureGates(nil, time.Second)
+		if err == nil {
+			t.Fatal("expected an error for a nil FeatureGateAccessor, got none")
+		}
+	})
+}
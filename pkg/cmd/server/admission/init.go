@@ -2,6 +2,7 @@ package admission
 
 import (
 	"k8s.io/kubernetes/pkg/admission"
+	"k8s.io/kubernetes/pkg/auth/authenticator"
 	"k8s.io/kubernetes/pkg/client/restclient"
 	"k8s.io/kubernetes/pkg/quota"
 
@@ -18,6 +19,9 @@ type PluginInitializer struct {
 	Authorizer            authorizer.Authorizer
 	JenkinsPipelineConfig configapi.JenkinsPipelineConfig
 	RESTClientConfig      restclient.Config
+	FeatureGateAccessor   FeatureGateAccessor
+	Authenticator         authenticator.Request
+	TokenFormatPolicy     TokenFormatPolicy
 }
 
 // Initialize will check the initialization interfaces implemented by each plugin
@@ -42,6 +46,15 @@ func (i *PluginInitializer) Initialize(plugins []admission.Interface) {
 		if wantsRESTClientConfig, ok := plugin.(WantsRESTClientConfig); ok {
 			wantsRESTClientConfig.SetRESTClientConfig(i.RESTClientConfig)
 		}
+		if wantsFeatureGates, ok := plugin.(WantsFeatureGates); ok {
+			wantsFeatureGates.SetFeatureGateAccessor(i.FeatureGateAccessor)
+		}
+		if wantsAuthenticator, ok := plugin.(WantsAuthenticator); ok {
+			wantsAuthenticator.SetAuthenticator(i.Authenticator)
+		}
+		if wantsTokenFormatPolicy, ok := plugin.(WantsTokenFormatPolicy); ok {
+			wantsTokenFormatPolicy.SetTokenFormatPolicy(i.TokenFormatPolicy)
+		}
 	}
 }
 
@@ -0,0 +1,52 @@
+package admission
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/auth/authenticator"
+)
+
+// TokenFormatPolicy describes the bearer token formats an admission plugin should accept when it
+// re-authenticates a token embedded in a resource payload (e.g. a build source secret or a template
+// parameter) rather than trusting the value it was handed.
+type TokenFormatPolicy struct {
+	// AllowedPrefixes lists the prefixes an embedded token is required to start with, e.g. "sha256~"
+	// for the hashed secret token format.  A nil or empty slice allows any prefix, including the legacy
+	// opaque token format; set it to reject legacy opaque tokens in embedded payloads ahead of
+	// rejecting them at the authenticator itself.
+	AllowedPrefixes []string
+
+	// MinLength is the minimum number of characters an embedded token must have.  Zero means no
+	// minimum is enforced.
+	MinLength int
+}
+
+// Validate returns an error describing why token does not conform to the policy, or nil if it does.
+func (p TokenFormatPolicy) Validate(token string) error {
+	if p.MinLength > 0 && len(token) < p.MinLength {
+		return fmt.Errorf("token is shorter than the minimum length of %d", p.MinLength)
+	}
+	if len(p.AllowedPrefixes) == 0 {
+		return nil
+	}
+	for _, prefix := range p.AllowedPrefixes {
+		if strings.HasPrefix(token, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("token does not have one of the allowed prefixes %v", p.AllowedPrefixes)
+}
+
+// WantsAuthenticator should be implemented by admission plugins that need to re-authenticate a bearer
+// token carried inside a resource payload (e.g. a build source secret or a template parameter) rather
+// than implicitly trusting the user making the request.
+type WantsAuthenticator interface {
+	SetAuthenticator(authenticator.Request)
+}
+
+// WantsTokenFormatPolicy should be implemented by admission plugins that inspect or forward embedded
+// tokens and need to enforce a consistent format policy across the apiserver.
+type WantsTokenFormatPolicy interface {
+	SetTokenFormatPolicy(TokenFormatPolicy)
+}
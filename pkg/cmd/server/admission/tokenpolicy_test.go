@@ -0,0 +1,159 @@
+package admission
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/admission"
+	"k8s.io/kubernetes/pkg/auth/authenticator"
+	"k8s.io/kubernetes/pkg/auth/user"
+)
+
+// fakeAuthenticator is a stand-in for the apiserver's real bearer token authenticator.  It treats any
+// Authorization header value ("Bearer <token>") present in tokenUsers as valid and everything else
+// (including well-formed-looking but unknown
+// legacy tokens) as unauthenticated, the same way re-authenticating an embedded token against the real
+// authenticator would reject one that doesn't correspond to a live token.
+type fakeAuthenticator struct {
+	tokenUsers map[string]user.Info
+}
+
+func (f *fakeAuthenticator) AuthenticateRequest(req *http.Request) (user.Info, bool, error) {
+	info, ok := f.tokenUsers[req.Header.Get("Authorization")]
+	return info, ok, nil
+}
+
+// fakeBuildSecretPlugin stands in for an admission plugin that re-authenticates the token embedded in
+// a BuildConfig's source secret before trusting it, rather than blindly forwarding whatever the user
+// supplied.
+type fakeBuildSecretPlugin struct {
+	policy        TokenFormatPolicy
+	authenticator authenticator.Request
+}
+
+func (p *fakeBuildSecretPlugin) Admit(a admission.Attributes) error { return nil }
+func (p *fakeBuildSecretPlugin) Handles(o admission.Operation) bool { return true }
+
+func (p *fakeBuildSecretPlugin) SetTokenFormatPolicy(policy TokenFormatPolicy) {
+	p.policy = policy
+}
+
+func (p *fakeBuildSecretPlugin) SetAuthenticator(a authenticator.Request) {
+	p.authenticator = a
+}
+
+// admitSourceSecretToken is what the plugin's real Admit() would call once it has pulled the token out
+// of the BuildConfig's source secret: first enforce the format policy, then re-authenticate the token
+// against the live authenticator rather than trusting that it belongs to the requesting user.
+func (p *fakeBuildSecretPlugin) admitSourceSecretToken(token string) error {
+	if err := p.policy.Validate(token); err != nil {
+		return fmt.Errorf("build source secret token rejected: %v", err)
+	}
+
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if _, ok, err := p.authenticator.AuthenticateRequest(req); err != nil {
+		return fmt.Errorf("build source secret token could not be authenticated: %v", err)
+	} else if !ok {
+		return fmt.Errorf("build source secret token does not correspond to a valid user")
+	}
+	return nil
+}
+
+func TestPluginInitializerWiresTokenFormatPolicy(t *testing.T) {
+	plugin := &fakeBuildSecretPlugin{}
+	policy := TokenFormatPolicy{AllowedPrefixes: []string{"sha256~"}}
+	initializer := &PluginInitializer{TokenFormatPolicy: policy}
+
+	initializer.Initialize([]admission.Interface{plugin})
+
+	if len(plugin.policy.AllowedPrefixes) != 1 || plugin.policy.AllowedPrefixes[0] != "sha256~" {
+		t.Fatalf("expected plugin to receive the configured TokenFormatPolicy, got %#v", plugin.policy)
+	}
+}
+
+func TestPluginInitializerWiresAuthenticator(t *testing.T) {
+	plugin := &fakeBuildSecretPlugin{}
+	auth := &fakeAuthenticator{tokenUsers: map[string]user.Info{
+		"Bearer sha256~validtoken": &user.DefaultInfo{Name: "builder"},
+	}}
+	initializer := &PluginInitializer{Authenticator: auth}
+
+	initializer.Initialize([]admission.Interface{plugin})
+
+	if plugin.authenticator == nil {
+		t.Fatalf("expected plugin to receive a non-nil Authenticator")
+	}
+	if _, ok, _ := plugin.authenticator.AuthenticateRequest(&http.Request{Header: http.Header{"Authorization": []string{"Bearer sha256~validtoken"}}}); !ok {
+		t.Fatalf("expected the wired authenticator to recognize the token it was configured with")
+	}
+}
+
+func TestTokenFormatPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    TokenFormatPolicy
+		token     string
+		expectErr bool
+	}{
+		{name: "no constraints accepts anything", policy: TokenFormatPolicy{}, token: "anything", expectErr: false},
+		{name: "allowed prefix is accepted", policy: TokenFormatPolicy{AllowedPrefixes: []string{"sha256~"}}, token: "sha256~abc", expectErr: false},
+		{
+			name:      "token without an allowed prefix is rejected even without an explicit opt-in flag",
+			policy:    TokenFormatPolicy{AllowedPrefixes: []string{"sha256~"}},
+			token:     "abc",
+			expectErr: true,
+		},
+		{name: "token shorter than MinLength is rejected", policy: TokenFormatPolicy{MinLength: 10}, token: "short", expectErr: true},
+		{name: "token meeting MinLength is accepted", policy: TokenFormatPolicy{MinLength: 5}, token: "longenough", expectErr: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.policy.Validate(test.token)
+			if test.expectErr && err == nil {
+				t.Fatalf("expected an error for token %q against policy %#v, got none", test.token, test.policy)
+			}
+			if !test.expectErr && err != nil {
+				t.Fatalf("expected no error for token %q against policy %#v, got: %v", test.token, test.policy, err)
+			}
+		})
+	}
+}
+
+func TestFakeBuildSecretPluginRejectsLegacyToken(t *testing.T) {
+	plugin := &fakeBuildSecretPlugin{
+		policy: TokenFormatPolicy{
+			AllowedPrefixes: []string{"sha256~"},
+		},
+		authenticator: &fakeAuthenticator{tokenUsers: map[string]user.Info{
+			"Bearer sha256~5f6b2e9c4a7d4e3f9a1b2c3d4e5f6071": &user.DefaultInfo{Name: "builder"},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		expectErr bool
+	}{
+		{name: "legacy opaque token is rejected by the format policy", token: "5f6b2e9c4a7d4e3f9a1b2c3d4e5f6071", expectErr: true},
+		{name: "sha256-prefixed token is admitted", token: "sha256~5f6b2e9c4a7d4e3f9a1b2c3d4e5f6071", expectErr: false},
+		{name: "well-formed but unknown token is rejected by re-authentication", token: "sha256~unknowntoken", expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := plugin.admitSourceSecretToken(test.token)
+			if test.expectErr && err == nil {
+				t.Fatalf("expected an error rejecting token %q, got none", test.token)
+			}
+			if !test.expectErr && err != nil {
+				t.Fatalf("expected no error for token %q, got: %v", test.token, err)
+			}
+		})
+	}
+}
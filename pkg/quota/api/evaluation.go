@@ -0,0 +1,99 @@
+package api
+
+import (
+	"fmt"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+)
+
+// NamespaceScopedQuota pairs a ResourceQuotaSpec that should be enforced for a namespace with the
+// ClusterResourceQuotaScope usage against it should be recorded under.  Scope is empty for the
+// unscoped, overall ClusterResourceQuotaSpec.Quota.
+type NamespaceScopedQuota struct {
+	Scope ClusterResourceQuotaScope
+	Quota kapi.ResourceQuotaSpec
+}
+
+// ResourceQuotasForNamespace returns every ResourceQuotaSpec a CRQ controller or admission plugin
+// should enforce against namespace: the unscoped overall Quota (the caller is expected to have already
+// matched namespace against Spec.Selector before calling this), plus any ScopedQuotas entry whose
+// ScopeSelector also matches namespace.  Each entry is paired with the ClusterResourceQuotaScope usage
+// computed against it should be recorded under, so the result can be written straight into
+// ClusterResourceQuotaStatus.Namespaces via ResourceQuotasStatusByNamespaceKey.
+func ResourceQuotasForNamespace(spec ClusterResourceQuotaSpec, namespace *kapi.Namespace) ([]NamespaceScopedQuota, error) {
+	result := []NamespaceScopedQuota{{Quota: spec.Quota}}
+
+	for _, scoped := range spec.ScopedQuotas {
+		matches, err := scoped.ScopeSelector.MatchesNamespace(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
+		result = append(result, NamespaceScopedQuota{Scope: scoped.ScopeSelector.primaryScope(), Quota: scoped.Quota})
+	}
+
+	return result, nil
+}
+
+// primaryScope returns the ClusterResourceQuotaScope that usage accrued under this selector should be
+// keyed by: the ScopeName of its first requirement, or empty if it has none.  A ScopedClusterResourceQuota
+// entry is expected to name one scope per entry; ANDing multiple built-in scopes together (e.g.
+// Terminating AND BestEffort) is legal but still only needs one key to bucket its usage under.
+func (s ScopeSelector) primaryScope() ClusterResourceQuotaScope {
+	if len(s.MatchExpressions) == 0 {
+		return ""
+	}
+	return s.MatchExpressions[0].ScopeName
+}
+
+// MatchesNamespace reports whether namespace satisfies every requirement in s.  Requirements naming a
+// built-in object scope (Terminating, NotTerminating, BestEffort, NotBestEffort) always pass here: those
+// scopes constrain individual objects, not namespaces, and are evaluated by the existing per-object
+// ResourceQuota evaluator instead.  Requirements naming ClusterResourceQuotaScopeNamespaceSelector are
+// evaluated against namespace's labels and annotations.
+func (s ScopeSelector) MatchesNamespace(namespace *kapi.Namespace) (bool, error) {
+	for _, requirement := range s.MatchExpressions {
+		if requirement.ScopeName != ClusterResourceQuotaScopeNamespaceSelector {
+			continue
+		}
+		matches, err := requirement.matchesNamespaceMetadata(namespace)
+		if err != nil {
+			return false, err
+		}
+		if !matches {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r ScopedResourceSelectorRequirement) matchesNamespaceMetadata(namespace *kapi.Namespace) (bool, error) {
+	value, exists := namespace.Labels[r.Key]
+	if !exists {
+		value, exists = namespace.Annotations[r.Key]
+	}
+
+	switch r.Operator {
+	case ScopeSelectorOpExists:
+		return exists, nil
+	case ScopeSelectorOpDoesNotExist:
+		return !exists, nil
+	case ScopeSelectorOpIn:
+		return exists && containsString(r.Values, value), nil
+	case ScopeSelectorOpNotIn:
+		return !exists || !containsString(r.Values, value), nil
+	default:
+		return false, fmt.Errorf("unsupported scope selector operator %q", r.Operator)
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"container/list"
+	"strings"
 
 	kapi "k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/api/unversioned"
@@ -29,8 +30,86 @@ type ClusterResourceQuotaSpec struct {
 	// this resource.
 	Selector *unversioned.LabelSelector
 
-	// Quota defines the desired quota
+	// Quota defines the desired overall quota.  It applies to every namespace matched by Selector,
+	// regardless of scope.
 	Quota kapi.ResourceQuotaSpec
+
+	// ScopedQuotas optionally narrows the overall Quota down further: each entry enforces its own
+	// Quota against only the subset of namespaces (and, for the built-in object scopes, objects)
+	// matched by its ScopeSelector.  This lets a single CRQ enforce different limits for, say,
+	// Terminating pods vs. BestEffort pods, or a namespace-labeled subset of the projects matched by
+	// Selector.
+	ScopedQuotas []ScopedClusterResourceQuota
+}
+
+// ScopedClusterResourceQuota pairs a quota restriction with the ScopeSelector it applies to.
+type ScopedClusterResourceQuota struct {
+	// ScopeSelector determines which namespaces and objects this entry's Quota is evaluated against.
+	ScopeSelector ScopeSelector
+
+	// Quota defines the restriction enforced for namespaces/objects selected by ScopeSelector
+	Quota kapi.ResourceQuotaSpec
+}
+
+// ClusterResourceQuotaScope identifies the subset of namespaces or objects a ScopedClusterResourceQuota
+// entry applies to.  The built-in values mirror kapi.ResourceQuotaScope so the same object-level
+// evaluation ResourceQuota already does for Terminating/BestEffort pods can be reused here;
+// ClusterResourceQuotaScopeNamespaceSelector is a CRQ-specific addition that filters on namespace
+// labels/annotations instead of a property of the object being counted.
+type ClusterResourceQuotaScope string
+
+const (
+	ClusterResourceQuotaScopeTerminating    ClusterResourceQuotaScope = ClusterResourceQuotaScope(kapi.ResourceQuotaScopeTerminating)
+	ClusterResourceQuotaScopeNotTerminating ClusterResourceQuotaScope = ClusterResourceQuotaScope(kapi.ResourceQuotaScopeNotTerminating)
+	ClusterResourceQuotaScopeBestEffort     ClusterResourceQuotaScope = ClusterResourceQuotaScope(kapi.ResourceQuotaScopeBestEffort)
+	ClusterResourceQuotaScopeNotBestEffort  ClusterResourceQuotaScope = ClusterResourceQuotaScope(kapi.ResourceQuotaScopeNotBestEffort)
+
+	// ClusterResourceQuotaScopeNamespaceSelector restricts a ScopedClusterResourceQuota entry to
+	// namespaces whose labels/annotations satisfy the ScopeSelector's MatchExpressions, rather than to
+	// a property of the objects being counted.
+	ClusterResourceQuotaScopeNamespaceSelector ClusterResourceQuotaScope = "NamespaceSelector"
+)
+
+// ScopeSelectorOperator is the set of operators a ScopedResourceSelectorRequirement can use to relate
+// a namespace's labels/annotations to Values.
+type ScopeSelectorOperator string
+
+const (
+	ScopeSelectorOpIn           ScopeSelectorOperator = "In"
+	ScopeSelectorOpNotIn        ScopeSelectorOperator = "NotIn"
+	ScopeSelectorOpExists       ScopeSelectorOperator = "Exists"
+	ScopeSelectorOpDoesNotExist ScopeSelectorOperator = "DoesNotExist"
+)
+
+// ScopedResourceSelectorRequirement relates a ClusterResourceQuotaScope to a set of Values via
+// Operator.  For the built-in object scopes (Terminating, NotTerminating, BestEffort,
+// NotBestEffort), Key and Values are ignored: those scopes are evaluated per-object by the existing
+// ResourceQuota machinery, not against the namespace.  For ClusterResourceQuotaScopeNamespaceSelector,
+// Key names the namespace label or annotation to inspect and Values is matched against its value the
+// same way a LabelSelector requirement would be.
+type ScopedResourceSelectorRequirement struct {
+	// ScopeName is the scope this requirement applies to
+	ScopeName ClusterResourceQuotaScope
+
+	// Key is the namespace label or annotation key this requirement matches against.  Only meaningful
+	// when ScopeName is ClusterResourceQuotaScopeNamespaceSelector.
+	Key string
+
+	// Operator relates Key's value to Values
+	Operator ScopeSelectorOperator
+
+	// Values is an array of string values, interpreted according to Operator.  May be empty for
+	// Exists/DoesNotExist.
+	Values []string
+}
+
+// ScopeSelector selects the namespaces/objects a ScopedClusterResourceQuota entry applies to,
+// mirroring upstream ResourceQuotaSpec.ScopeSelector but additionally supporting the
+// ClusterResourceQuotaScopeNamespaceSelector scope.
+type ScopeSelector struct {
+	// MatchExpressions is a list of requirements ANDed together to determine whether a namespace or
+	// object is in scope
+	MatchExpressions []ScopedResourceSelectorRequirement
 }
 
 // ClusterResourceQuotaStatus defines the actual enforced quota and its current usage
@@ -54,29 +133,66 @@ type ClusterResourceQuotaList struct {
 	Items []ClusterResourceQuota
 }
 
-// ResourceQuotasStatusByNamespace provides type correct methods
+// ResourceQuotasStatusByNamespace provides type correct methods.  Usage is tracked per
+// (namespace, scope) pair rather than per namespace alone, so usage accrued against a
+// ScopedClusterResourceQuota entry doesn't clobber the unscoped usage tracked for the same namespace
+// against the overall Quota.
 type ResourceQuotasStatusByNamespace struct {
 	orderedMap orderedMap
 }
 
-func (o *ResourceQuotasStatusByNamespace) Insert(key string, value kapi.ResourceQuotaStatus) {
-	o.orderedMap.Insert(key, value)
+// ResourceQuotasStatusByNamespaceKey identifies one (namespace, scope) bucket of usage.  Scope is
+// empty for usage tracked against ClusterResourceQuotaSpec.Quota, the unscoped overall quota.
+type ResourceQuotasStatusByNamespaceKey struct {
+	Namespace string
+	Scope     ClusterResourceQuotaScope
+}
+
+// String returns the composite string used as the backing map key.  It's kept namespace-only (with
+// no separator) when Scope is empty so existing unscoped keys serialize exactly as before.
+func (k ResourceQuotasStatusByNamespaceKey) String() string {
+	if len(k.Scope) == 0 {
+		return k.Namespace
+	}
+	return k.Namespace + "/" + string(k.Scope)
 }
 
-func (o *ResourceQuotasStatusByNamespace) Get(key string) (kapi.ResourceQuotaStatus, bool) {
-	ret, ok := o.orderedMap.Get(key)
+// ParseResourceQuotasStatusByNamespaceKey parses a composite key produced by
+// ResourceQuotasStatusByNamespaceKey.String() back into its Namespace/Scope parts.  Namespace names
+// can't contain "/", so splitting on the first occurrence is unambiguous.
+func ParseResourceQuotasStatusByNamespaceKey(key string) ResourceQuotasStatusByNamespaceKey {
+	if idx := strings.Index(key, "/"); idx != -1 {
+		return ResourceQuotasStatusByNamespaceKey{Namespace: key[:idx], Scope: ClusterResourceQuotaScope(key[idx+1:])}
+	}
+	return ResourceQuotasStatusByNamespaceKey{Namespace: key}
+}
+
+func (o *ResourceQuotasStatusByNamespace) Insert(key ResourceQuotasStatusByNamespaceKey, value kapi.ResourceQuotaStatus) {
+	o.orderedMap.Insert(key.String(), value)
+}
+
+func (o *ResourceQuotasStatusByNamespace) Get(key ResourceQuotasStatusByNamespaceKey) (kapi.ResourceQuotaStatus, bool) {
+	ret, ok := o.orderedMap.Get(key.String())
 	if !ok {
 		return kapi.ResourceQuotaStatus{}, ok
 	}
 	return ret.(kapi.ResourceQuotaStatus), ok
 }
 
-func (o *ResourceQuotasStatusByNamespace) Remove(key string) {
-	o.orderedMap.Remove(key)
+func (o *ResourceQuotasStatusByNamespace) Remove(key ResourceQuotasStatusByNamespaceKey) {
+	o.orderedMap.Remove(key.String())
 }
 
-func (o *ResourceQuotasStatusByNamespace) OrderedKeys() *list.List {
-	return o.orderedMap.OrderedKeys()
+// OrderedKeys returns the (namespace, scope) keys in insertion order, so callers can round-trip
+// enumeration (e.g. pruning deleted namespaces, rebuilding ClusterResourceQuotaStatus) without having
+// to hand-parse the backing map's composite string keys themselves.
+func (o *ResourceQuotasStatusByNamespace) OrderedKeys() []ResourceQuotasStatusByNamespaceKey {
+	raw := o.orderedMap.OrderedKeys()
+	keys := make([]ResourceQuotasStatusByNamespaceKey, 0, raw.Len())
+	for e := raw.Front(); e != nil; e = e.Next() {
+		keys = append(keys, ParseResourceQuotasStatusByNamespaceKey(e.Value.(string)))
+	}
+	return keys
 }
 
 // orderedMap is a very simple ordering a map tracking insertion order.  It allows fast and stable serializations
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/resource"
+)
+
+func TestResourceQuotasStatusByNamespaceKeyString(t *testing.T) {
+	tests := []struct {
+		name string
+		key  ResourceQuotasStatusByNamespaceKey
+		want string
+	}{
+		{name: "unscoped key has no separator", key: ResourceQuotasStatusByNamespaceKey{Namespace: "ns"}, want: "ns"},
+		{name: "scoped key is namespace/scope", key: ResourceQuotasStatusByNamespaceKey{Namespace: "ns", Scope: ClusterResourceQuotaScopeBestEffort}, want: "ns/BestEffort"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.key.String(); got != test.want {
+				t.Errorf("String() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseResourceQuotasStatusByNamespaceKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want ResourceQuotasStatusByNamespaceKey
+	}{
+		{name: "unscoped key", key: "ns", want: ResourceQuotasStatusByNamespaceKey{Namespace: "ns"}},
+		{name: "scoped key", key: "ns/BestEffort", want: ResourceQuotasStatusByNamespaceKey{Namespace: "ns", Scope: ClusterResourceQuotaScopeBestEffort}},
+		{
+			name: "scope value containing a slash is preserved after the first separator",
+			key:  "ns/NamespaceSelector/extra",
+			want: ResourceQuotasStatusByNamespaceKey{Namespace: "ns", Scope: "NamespaceSelector/extra"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ParseResourceQuotasStatusByNamespaceKey(test.key); got != test.want {
+				t.Errorf("ParseResourceQuotasStatusByNamespaceKey(%q) = %#v, want %#v", test.key, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResourceQuotasStatusByNamespaceKeyRoundTrip(t *testing.T) {
+	keys := []ResourceQuotasStatusByNamespaceKey{
+		{Namespace: "ns"},
+		{Namespace: "ns", Scope: ClusterResourceQuotaScopeBestEffort},
+	}
+	for _, key := range keys {
+		if got := ParseResourceQuotasStatusByNamespaceKey(key.String()); got != key {
+			t.Errorf("round-trip through String()/Parse() produced %#v, want %#v", got, key)
+		}
+	}
+}
+
+func TestResourceQuotasStatusByNamespaceOrderedKeys(t *testing.T) {
+	var byNamespace ResourceQuotasStatusByNamespace
+
+	unscopedFoo := ResourceQuotasStatusByNamespaceKey{Namespace: "foo"}
+	scopedFoo := ResourceQuotasStatusByNamespaceKey{Namespace: "foo", Scope: ClusterResourceQuotaScopeBestEffort}
+	unscopedBar := ResourceQuotasStatusByNamespaceKey{Namespace: "bar"}
+
+	byNamespace.Insert(unscopedFoo, kapi.ResourceQuotaStatus{})
+	byNamespace.Insert(scopedFoo, kapi.ResourceQuotaStatus{})
+	byNamespace.Insert(unscopedBar, kapi.ResourceQuotaStatus{})
+
+	want := []ResourceQuotasStatusByNamespaceKey{unscopedFoo, scopedFoo, unscopedBar}
+	got := byNamespace.OrderedKeys()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("OrderedKeys() = %#v, want %#v", got, want)
+	}
+
+	byNamespace.Remove(scopedFoo)
+	got = byNamespace.OrderedKeys()
+	want = []ResourceQuotasStatusByNamespaceKey{unscopedFoo, unscopedBar}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("after Remove, OrderedKeys() = %#v, want %#v", got, want)
+	}
+	if _, ok := byNamespace.Get(scopedFoo); ok {
+		t.Fatalf("expected scopedFoo to be removed")
+	}
+}
+
+func TestResourceQuotasForNamespace(t *testing.T) {
+	namespace := &kapi.Namespace{
+		ObjectMeta: kapi.ObjectMeta{
+			Name:   "billing",
+			Labels: map[string]string{"team": "billing"},
+		},
+	}
+
+	spec := ClusterResourceQuotaSpec{
+		Quota: kapi.ResourceQuotaSpec{Hard: kapi.ResourceList{"pods": resource.MustParse("10")}},
+		ScopedQuotas: []ScopedClusterResourceQuota{
+			{
+				ScopeSelector: ScopeSelector{MatchExpressions: []ScopedResourceSelectorRequirement{
+					{ScopeName: ClusterResourceQuotaScopeNamespaceSelector, Key: "team", Operator: ScopeSelectorOpIn, Values: []string{"billing"}},
+				}},
+				Quota: kapi.ResourceQuotaSpec{Hard: kapi.ResourceList{"pods": resource.MustParse("2")}},
+			},
+			{
+				ScopeSelector: ScopeSelector{MatchExpressions: []ScopedResourceSelectorRequirement{
+					{ScopeName: ClusterResourceQuotaScopeNamespaceSelector, Key: "team", Operator: ScopeSelectorOpIn, Values: []string{"other"}},
+				}},
+				Quota: kapi.ResourceQuotaSpec{Hard: kapi.ResourceList{"pods": resource.MustParse("99")}},
+			},
+			{
+				ScopeSelector: ScopeSelector{MatchExpressions: []ScopedResourceSelectorRequirement{
+					{ScopeName: ClusterResourceQuotaScopeBestEffort},
+				}},
+				Quota: kapi.ResourceQuotaSpec{Hard: kapi.ResourceList{"pods": resource.MustParse("1")}},
+			},
+		},
+	}
+
+	quotas, err := ResourceQuotasForNamespace(spec, namespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(quotas) != 3 {
+		t.Fatalf("expected the unscoped quota plus the two matching scoped entries, got %d: %#v", len(quotas), quotas)
+	}
+	if quotas[0].Scope != "" {
+		t.Errorf("expected the first entry to be the unscoped overall quota, got scope %q", quotas[0].Scope)
+	}
+	if quotas[1].Scope != ClusterResourceQuotaScopeNamespaceSelector {
+		t.Errorf("expected the matching namespace-selector entry to be included, got %#v", quotas[1])
+	}
+	if quotas[2].Scope != ClusterResourceQuotaScopeBestEffort {
+		t.Errorf("expected the built-in BestEffort scope entry to always be included, got %#v", quotas[2])
+	}
+}
@@ -0,0 +1,138 @@
+// +k8s:conversion-gen=github.com/openshift/origin/pkg/quota/api
+package v1
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// ClusterResourceQuota mirrors ResourceQuota at a cluster scope.  This object is easily convertible to
+// synthetic ResourceQuota object to allow quota evaluation re-use.
+type ClusterResourceQuota struct {
+	unversioned.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	kapi.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired quota
+	Spec ClusterResourceQuotaSpec `json:"spec"`
+
+	// Status defines the actual enforced quota and its current usage
+	Status ClusterResourceQuotaStatus `json:"status,omitempty"`
+}
+
+// ClusterResourceQuotaSpec defines the desired quota restrictions
+type ClusterResourceQuotaSpec struct {
+	// Selector is the label selector used to match projects.  It is not allowed to be empty
+	// and should only select active projects on the scale of dozens (though it can select
+	// many more less active projects).  These projects will contend on object creation through
+	// this resource.
+	Selector *unversioned.LabelSelector `json:"selector"`
+
+	// Quota defines the desired overall quota.  It applies to every namespace matched by Selector,
+	// regardless of scope.
+	Quota kapi.ResourceQuotaSpec `json:"quota"`
+
+	// ScopedQuotas optionally narrows the overall Quota down further: each entry enforces its own
+	// Quota against only the subset of namespaces (and, for the built-in object scopes, objects)
+	// matched by its ScopeSelector.
+	ScopedQuotas []ScopedClusterResourceQuota `json:"scopedQuotas,omitempty"`
+}
+
+// ScopedClusterResourceQuota pairs a quota restriction with the ScopeSelector it applies to.
+type ScopedClusterResourceQuota struct {
+	// ScopeSelector determines which namespaces and objects this entry's Quota is evaluated against.
+	ScopeSelector ScopeSelector `json:"scopeSelector"`
+
+	// Quota defines the restriction enforced for namespaces/objects selected by ScopeSelector
+	Quota kapi.ResourceQuotaSpec `json:"quota"`
+}
+
+// ClusterResourceQuotaScope identifies the subset of namespaces or objects a ScopedClusterResourceQuota
+// entry applies to.  The built-in values mirror kapi.ResourceQuotaScope; NamespaceSelector is a
+// CRQ-specific addition that filters on namespace labels/annotations instead.
+type ClusterResourceQuotaScope string
+
+const (
+	ClusterResourceQuotaScopeTerminating       ClusterResourceQuotaScope = ClusterResourceQuotaScope(kapi.ResourceQuotaScopeTerminating)
+	ClusterResourceQuotaScopeNotTerminating    ClusterResourceQuotaScope = ClusterResourceQuotaScope(kapi.ResourceQuotaScopeNotTerminating)
+	ClusterResourceQuotaScopeBestEffort        ClusterResourceQuotaScope = ClusterResourceQuotaScope(kapi.ResourceQuotaScopeBestEffort)
+	ClusterResourceQuotaScopeNotBestEffort     ClusterResourceQuotaScope = ClusterResourceQuotaScope(kapi.ResourceQuotaScopeNotBestEffort)
+	ClusterResourceQuotaScopeNamespaceSelector ClusterResourceQuotaScope = "NamespaceSelector"
+)
+
+// ScopeSelectorOperator is the set of operators a ScopedResourceSelectorRequirement can use to relate
+// a namespace's labels/annotations to Values.
+type ScopeSelectorOperator string
+
+const (
+	ScopeSelectorOpIn           ScopeSelectorOperator = "In"
+	ScopeSelectorOpNotIn        ScopeSelectorOperator = "NotIn"
+	ScopeSelectorOpExists       ScopeSelectorOperator = "Exists"
+	ScopeSelectorOpDoesNotExist ScopeSelectorOperator = "DoesNotExist"
+)
+
+// ScopedResourceSelectorRequirement relates a ClusterResourceQuotaScope to a set of Values via
+// Operator.  Key names the namespace label or annotation to inspect and is only meaningful when
+// ScopeName is NamespaceSelector.
+type ScopedResourceSelectorRequirement struct {
+	// ScopeName is the scope this requirement applies to
+	ScopeName ClusterResourceQuotaScope `json:"scopeName"`
+
+	// Key is the namespace label or annotation key this requirement matches against
+	Key string `json:"key,omitempty"`
+
+	// Operator relates Key's value to Values
+	Operator ScopeSelectorOperator `json:"operator"`
+
+	// Values is an array of string values, interpreted according to Operator.  May be empty for
+	// Exists/DoesNotExist.
+	Values []string `json:"values,omitempty"`
+}
+
+// ScopeSelector selects the namespaces/objects a ScopedClusterResourceQuota entry applies to,
+// mirroring upstream ResourceQuotaSpec.ScopeSelector but additionally supporting the
+// NamespaceSelector scope.
+type ScopeSelector struct {
+	// MatchExpressions is a list of requirements ANDed together to determine whether a namespace or
+	// object is in scope
+	MatchExpressions []ScopedResourceSelectorRequirement `json:"matchExpressions,omitempty"`
+}
+
+// ClusterResourceQuotaStatus defines the actual enforced quota and its current usage
+type ClusterResourceQuotaStatus struct {
+	// Total defines the actual enforced quota and its current usage across all namespaces
+	Total kapi.ResourceQuotaStatus `json:"total"`
+
+	// Namespaces slices the usage by namespace (and scope).  This division allows for quick resolution
+	// of deletion reconcilation inside of a single namespace without requiring a recalculation across
+	// all namespaces.
+	Namespaces ResourceQuotasStatusByNamespace `json:"namespaces,omitempty"`
+}
+
+// ResourceQuotasStatusByNamespace is the wire format for api.ResourceQuotasStatusByNamespace: an
+// ordered list of per-(namespace, scope) usage entries, preserving insertion order the same way the
+// internal type's orderedMap does.
+type ResourceQuotasStatusByNamespace []ResourceQuotaStatusByNamespace
+
+// ResourceQuotaStatusByNamespace is one (namespace, scope) usage entry.  Scope is empty for usage
+// tracked against the unscoped, overall ClusterResourceQuotaSpec.Quota.
+type ResourceQuotaStatusByNamespace struct {
+	// Namespace the quota applies to
+	Namespace string `json:"namespace"`
+
+	// Scope the usage was accrued under, or empty for the unscoped overall quota
+	Scope ClusterResourceQuotaScope `json:"scope,omitempty"`
+
+	// Status is the current enforced quota and usage for this namespace/scope
+	Status kapi.ResourceQuotaStatus `json:"status"`
+}
+
+// ClusterResourceQuotaList is a collection of ClusterResourceQuotas
+type ClusterResourceQuotaList struct {
+	unversioned.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	unversioned.ListMeta `json:"metadata,omitempty"`
+
+	// Items is a list of ClusterResourceQuotas
+	Items []ClusterResourceQuota `json:"items"`
+}
@@ -6,6 +6,11 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/golang/glog"
@@ -16,47 +21,42 @@ import (
 	"k8s.io/kubernetes/pkg/util/sets"
 )
 
+// conversionTags are the comment markers that flag a types.go as wanting generated conversions.
+// Both are recognized because origin's own API packages predate the k8s:-prefixed convention that
+// supportingPackages' k8s.io/kubernetes siblings now use.
+var conversionTags = []string{"+k8s:conversion-gen", "+genconversion"}
+
+// allowPackages and denyPackages let us force a package in or out of discovery without having to restructure the
+// tree just to dodge the walk.  Keep this list small; it's meant for exceptions, not the common case.
+var (
+	allowPackages = sets.NewString()
+	denyPackages  = sets.NewString()
+)
+
+// supportingPackages are the k8s.io/kubernetes packages whose types and conversion functions the
+// discovered origin packages depend on.  These live outside pkg/**/api/v1 so they can't be found by
+// walking this repository and are kept as a fixed list.
+var supportingPackages = sets.NewString(
+	"k8s.io/kubernetes/pkg/api/v1",
+	"k8s.io/kubernetes/pkg/api",
+	"k8s.io/kubernetes/pkg/runtime",
+	"k8s.io/kubernetes/pkg/conversion",
+)
+
 func main() {
 	arguments := args.Default()
 
-	// These are the packages we expect generated conversions for
-	expectedPackages := sets.NewString(
-		"github.com/openshift/origin/pkg/authorization/api/v1",
-		"github.com/openshift/origin/pkg/build/api/v1",
-		"github.com/openshift/origin/pkg/deploy/api/v1",
-		"github.com/openshift/origin/pkg/image/api/v1",
-		"github.com/openshift/origin/pkg/oauth/api/v1",
-		"github.com/openshift/origin/pkg/project/api/v1",
-		"github.com/openshift/origin/pkg/quota/api/v1",
-		"github.com/openshift/origin/pkg/route/api/v1",
-		"github.com/openshift/origin/pkg/sdn/api/v1",
-		"github.com/openshift/origin/pkg/template/api/v1",
-		"github.com/openshift/origin/pkg/user/api/v1",
-		"github.com/openshift/origin/pkg/security/api/v1",
-	)
-
-	// These are the packages containing types and conversion functions used by the packages we want to generate for
-	supportingPackages := sets.NewString(
-		"k8s.io/kubernetes/pkg/api/v1",
-		"k8s.io/kubernetes/pkg/api",
-		"k8s.io/kubernetes/pkg/runtime",
-		"k8s.io/kubernetes/pkg/conversion",
-		"github.com/openshift/origin/pkg/authorization/api",
-		"github.com/openshift/origin/pkg/build/api",
-		"github.com/openshift/origin/pkg/deploy/api",
-		"github.com/openshift/origin/pkg/image/api",
-		"github.com/openshift/origin/pkg/oauth/api",
-		"github.com/openshift/origin/pkg/project/api",
-		"github.com/openshift/origin/pkg/quota/api",
-		"github.com/openshift/origin/pkg/route/api",
-		"github.com/openshift/origin/pkg/sdn/api",
-		"github.com/openshift/origin/pkg/template/api",
-		"github.com/openshift/origin/pkg/user/api",
-		"github.com/openshift/origin/pkg/security/api",
-	)
-
-	// Override defaults. These are Kubernetes specific input locations.
-	arguments.InputDirs = sets.NewString().Union(expectedPackages).Union(supportingPackages).List()
+	expectedPackages, discoveredSupportingPackages, skipped := discoverPackages("pkg")
+	expectedPackages = expectedPackages.Difference(denyPackages).Union(allowPackages)
+	allSupportingPackages := supportingPackages.Union(discoveredSupportingPackages)
+
+	glog.Infof("conversion-gen: discovered %d input packages, %d supporting packages, skipped %d candidate directories",
+		len(expectedPackages), len(allSupportingPackages), len(skipped))
+	for _, path := range skipped {
+		glog.V(4).Infof("conversion-gen: skipped %s (no %s tag found)", path, strings.Join(conversionTags, " or "))
+	}
+
+	arguments.InputDirs = sets.NewString().Union(expectedPackages).Union(allSupportingPackages).List()
 
 	arguments.GoHeaderFilePath = "hack/boilerplate.txt"
 
@@ -84,6 +84,102 @@ func main() {
 	if missing := expectedPackages.Difference(foundPackages); len(missing) > 0 {
 		glog.Fatalf("Missing expected packages:\n%v", missing.List())
 	}
+	if empty := packagesMissingGeneratedFile(expectedPackages); len(empty) > 0 {
+		glog.Fatalf("Discovered packages produced zero generated files:\n%v", empty)
+	}
 
 	glog.Info("Completed successfully.")
 }
+
+// discoverPackages walks root looking for .../api/v1 directories whose doc.go or types.go is tagged
+// with one of conversionTags.  It returns the discovered v1 import paths, the corresponding internal api sibling
+// import paths (as supporting packages, since conversions are generated against them), and the list of
+// api/v1 candidate directories that were found but skipped for lacking the tag.
+func discoverPackages(root string) (sets.String, sets.String, []string) {
+	const originImportPrefix = "github.com/openshift/origin/"
+
+	expected := sets.NewString()
+	supporting := sets.NewString()
+	var skipped []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() || filepath.Base(path) != "v1" || filepath.Base(filepath.Dir(path)) != "api" {
+			return nil
+		}
+
+		importPath := originImportPrefix + filepath.ToSlash(path)
+		if !hasConversionTag(path) {
+			skipped = append(skipped, importPath)
+			return nil
+		}
+
+		expected.Insert(importPath)
+		supporting.Insert(originImportPrefix + filepath.ToSlash(filepath.Dir(path)))
+		return nil
+	})
+	if err != nil {
+		glog.Fatalf("Error walking %s for conversion-gen input packages: %v", root, err)
+	}
+
+	return expected, supporting, skipped
+}
+
+// hasConversionTag reports whether dir carries a conversion-gen marker that enables generation for the
+// package as a whole.  doc.go is checked first since that's the conventional home for a package-level
+// marker, falling back to types.go for packages that haven't split one out.
+func hasConversionTag(dir string) bool {
+	return fileHasConversionTag(filepath.Join(dir, "doc.go")) || fileHasConversionTag(filepath.Join(dir, "types.go"))
+}
+
+// fileHasConversionTag scans path line by line for one of conversionTags.  A tag is only treated as
+// enabling generation if it isn't suffixed with "=false": "+genconversion=false" is the documented
+// per-struct opt-out marker (see the file header), not a package-wide "generate" signal, and a naive
+// substring match against "+genconversion" would wrongly treat every opt-out as an opt-in while
+// packages with no per-struct exceptions (the common case) would never match at all.
+func fileHasConversionTag(path string) bool {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "//") {
+			continue
+		}
+		for _, tag := range conversionTags {
+			idx := strings.Index(line, tag)
+			if idx == -1 {
+				continue
+			}
+			if strings.HasPrefix(line[idx+len(tag):], "=false") {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// packagesMissingGeneratedFile returns the import paths in expected whose package directory has no
+// conversion_generated.go, i.e. the generator ran against them but emitted nothing.  This is what
+// catches a package that was discovered and processed, yet produced no conversions because, say, it
+// has no types requiring one.
+func packagesMissingGeneratedFile(expected sets.String) []string {
+	const originImportPrefix = "github.com/openshift/origin/"
+
+	var missing []string
+	for _, importPath := range expected.List() {
+		if !strings.HasPrefix(importPath, originImportPrefix) {
+			continue
+		}
+		dir := strings.TrimPrefix(importPath, originImportPrefix)
+		if _, err := os.Stat(filepath.Join(dir, "conversion_generated.go")); err != nil {
+			missing = append(missing, importPath)
+		}
+	}
+	return missing
+}
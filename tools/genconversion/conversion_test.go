@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileHasConversionTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     bool
+	}{
+		{
+			name:     "+k8s:conversion-gen=<package> tag enables generation",
+			contents: "// +k8s:conversion-gen=github.com/openshift/origin/pkg/quota/api\npackage v1\n",
+			want:     true,
+		},
+		{
+			name:     "bare +genconversion tag enables generation",
+			contents: "// +genconversion\npackage v1\n",
+			want:     true,
+		},
+		{
+			name:     "per-struct +genconversion=false opt-out does not enable generation",
+			contents: "package v1\n\n// +genconversion=false\ntype Foo struct{}\n",
+			want:     false,
+		},
+		{
+			name:     "no tag at all",
+			contents: "package v1\n\ntype Foo struct{}\n",
+			want:     false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "conversion-gen-test")
+			if err != nil {
+				t.Fatalf("unexpected error creating temp dir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			if err := ioutil.WriteFile(filepath.Join(dir, "types.go"), []byte(test.contents), 0644); err != nil {
+				t.Fatalf("unexpected error writing types.go: %v", err)
+			}
+
+			if got := hasConversionTag(dir); got != test.want {
+				t.Errorf("hasConversionTag() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}